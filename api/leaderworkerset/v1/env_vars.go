@@ -0,0 +1,51 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// Names of the environment variables AddLWSVariables (and, for MASTER_ADDR
+// and MASTER_PORT, AddMasterEnvAliases) injects into every container so that
+// distributed-training launchers (torchrun, MPI) can start without a wrapper
+// script. LwsLeaderAddress predates this set and
+// lives alongside it for historical reasons.
+const (
+	// LwsGroupSize is the number of pods in the group the container's pod
+	// belongs to.
+	LwsGroupSize = "LWS_GROUP_SIZE"
+	// LwsWorldSize is the total number of pods across every group in the
+	// LeaderWorkerSet, for LWS instances that represent a single logical
+	// distributed-training world. Only injected when the pod carries
+	// WorldSizeLabelKey.
+	LwsWorldSize = "LWS_WORLD_SIZE"
+	// LwsWorkerIndex is the pod's index within its group.
+	LwsWorkerIndex = "LWS_WORKER_INDEX"
+	// LwsGroupIndex is the index of the pod's group within the LeaderWorkerSet.
+	LwsGroupIndex = "LWS_GROUP_INDEX"
+	// LwsGlobalRank is groupIndex*groupSize + workerIndex.
+	LwsGlobalRank = "LWS_GLOBAL_RANK"
+	// LwsPeerAddresses is a comma-separated list of the DNS names of every
+	// pod in the group, including the pod's own address, ordered by worker
+	// index.
+	LwsPeerAddresses = "LWS_PEER_ADDRESSES"
+	// LwsMasterAddr aliases LwsLeaderAddress so launchers that expect
+	// torchrun/MPI conventions can be pointed at the leader without a
+	// wrapper script. Only injected when
+	// LeaderWorkerTemplate.RendezvousConfig.ExposeMasterEnv is set.
+	LwsMasterAddr = "MASTER_ADDR"
+	// LwsMasterPort aliases the leader's rendezvous port under the same
+	// condition as LwsMasterAddr.
+	LwsMasterPort = "MASTER_PORT"
+)
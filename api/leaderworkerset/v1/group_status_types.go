@@ -0,0 +1,49 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// GroupConditionType is a per-group readiness condition surfaced on
+// LeaderWorkerSet.Status, distinct from the set-level conditions already
+// reported there.
+type GroupConditionType string
+
+const (
+	// GroupAllMembersReady is true when every pod in the group, leader and
+	// workers, is ready.
+	GroupAllMembersReady GroupConditionType = "AllMembersReady"
+	// GroupLeaderReady is true when the group's leader pod is ready,
+	// independent of worker readiness.
+	GroupLeaderReady GroupConditionType = "LeaderReady"
+	// GroupPartiallyReady is true when at least one but not all of the
+	// group's pods are ready.
+	GroupPartiallyReady GroupConditionType = "PartiallyReady"
+)
+
+// GroupStatus reports one group's aggregate readiness, intended as an entry
+// in a new []GroupStatus field on LeaderWorkerSetStatus keyed by group
+// index.
+type GroupStatus struct {
+	// GroupIndex is the index of the group this status describes.
+	GroupIndex int `json:"groupIndex"`
+
+	// Conditions holds the group's AllMembersReady/LeaderReady/PartiallyReady
+	// conditions, with transition timestamps.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
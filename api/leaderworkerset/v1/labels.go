@@ -0,0 +1,41 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+const (
+	// PodIndexLabelKey is a stable, monotonically-increasing index for a
+	// worker pod within its group, analogous to StatefulSet's
+	// apps.kubernetes.io/pod-index. Unlike WorkerIndexLabelKey this is
+	// guarded by the PodIndexLabel feature gate.
+	PodIndexLabelKey = "leaderworkerset.sigs.k8s.io/pod-index"
+
+	// GlobalRankLabelKey is groupIndex*groupSize + workerIndex, a single
+	// rank spanning every group in the LeaderWorkerSet, for selectors and
+	// PodMonitors that need to address one replica across the whole set.
+	GlobalRankLabelKey = "leaderworkerset.sigs.k8s.io/global-rank"
+
+	// GroupSizeLabelKey records the size of a pod's group at creation time,
+	// so utilities that only see one pod at a time (e.g. AddLWSVariables)
+	// can still compute group-relative values without calling back to the
+	// API server.
+	GroupSizeLabelKey = "leaderworkerset.sigs.k8s.io/group-size"
+
+	// WorldSizeLabelKey records the total number of pods across every group
+	// in the LeaderWorkerSet, for sets that represent a single logical
+	// distributed-training world. Only present when the LWS spec enables it.
+	WorldSizeLabelKey = "leaderworkerset.sigs.k8s.io/world-size"
+)
@@ -0,0 +1,38 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// LifecycleHooks holds the timeouts for the pre-check/post-check handshake
+// on disruptive operations (upgrade, restart, scale-in, migration) against a
+// group's pods, whose label helpers live in pkg/utils/pod (RequestPreCheck,
+// ReadyForOperation, RequestPostCheck, PostCheckPassed). No controller in
+// this repository reads these fields or waits on the handshake before
+// recreating a group member.
+type LifecycleHooks struct {
+	// PreCheckTimeout is the bound on waiting for all registered checkers to
+	// set the pre-checked label before proceeding with the operation anyway.
+	// +optional
+	PreCheckTimeout *metav1.Duration `json:"preCheckTimeout,omitempty"`
+
+	// PostCheckTimeout is the bound on waiting for all registered checkers to
+	// set the post-checked label before considering the operation complete
+	// regardless.
+	// +optional
+	PostCheckTimeout *metav1.Duration `json:"postCheckTimeout,omitempty"`
+}
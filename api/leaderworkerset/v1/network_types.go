@@ -0,0 +1,68 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// NetworkConfig configures network-readiness behavior the pod webhook
+// injects alongside AddLWSVariables, intended as an optional field on
+// LeaderWorkerTemplate.
+type NetworkConfig struct {
+	// LeaderDNSPrewarm, when set, causes the webhook to inject an init
+	// container into every worker pod that blocks until LWS_LEADER_ADDRESS
+	// resolves and its port accepts a TCP connection, working around
+	// headless-service DNS propagation races on worker start.
+	// +optional
+	LeaderDNSPrewarm *LeaderDNSPrewarmConfig `json:"leaderDnsPrewarm,omitempty"`
+}
+
+// LeaderDNSPrewarmConfig configures the DNS pre-warming init container.
+type LeaderDNSPrewarmConfig struct {
+	// Image is the init container image used to resolve and probe the
+	// leader address. Defaults to the LWS sidecar image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Port is the TCP port probed on the leader address once it resolves.
+	Port int32 `json:"port"`
+
+	// TimeoutSeconds bounds, in seconds, how long the init container waits before
+	// failing the pod's startup.
+	// +optional
+	// +kubebuilder:default=60
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// RetryBackoffSeconds is the delay between resolution/connect attempts.
+	// +optional
+	// +kubebuilder:default=2
+	RetryBackoffSeconds int32 `json:"retryBackoffSeconds,omitempty"`
+
+	// WriteToEtcHosts additionally writes the resolved leader IP into
+	// /etc/hosts via a shared emptyDir mounted into the init container and
+	// every application container, so libraries that cache DNS at process
+	// start still see a stable address.
+	// +optional
+	WriteToEtcHosts bool `json:"writeToEtcHosts,omitempty"`
+}
+
+const (
+	// DefaultDNSPrewarmImage is used when LeaderDNSPrewarmConfig.Image is unset.
+	DefaultDNSPrewarmImage = "registry.k8s.io/lws/dns-prewarm:latest"
+	// DNSPrewarmContainerName is the name given to the injected init container.
+	DNSPrewarmContainerName = "lws-dns-prewarm"
+	// DNSPrewarmVolumeName is the shared emptyDir used to propagate
+	// /etc/hosts entries when WriteToEtcHosts is set.
+	DNSPrewarmVolumeName = "lws-dns-prewarm-hosts"
+)
@@ -0,0 +1,39 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// RendezvousConfig controls the MASTER_ADDR/MASTER_PORT aliases that
+// AddMasterEnvAliases injects into every container, on top of the rendezvous
+// variables AddLWSVariables always injects, intended to be embedded as an
+// optional field on LeaderWorkerTemplate.
+type RendezvousConfig struct {
+	// ExposeMasterEnv additionally injects MASTER_ADDR and MASTER_PORT,
+	// aliasing the leader's address and rendezvous port, so launchers such
+	// as torchrun or MPI can be invoked directly without a wrapper script.
+	// +optional
+	ExposeMasterEnv bool `json:"exposeMasterEnv,omitempty"`
+
+	// MasterPort is the rendezvous port advertised as MASTER_PORT when
+	// ExposeMasterEnv is set. Defaults to 29500, torchrun's own default.
+	// +optional
+	// +kubebuilder:default=29500
+	MasterPort int32 `json:"masterPort,omitempty"`
+}
+
+// DefaultMasterPort is advertised as MASTER_PORT when ExposeMasterEnv is set
+// and RendezvousConfig.MasterPort is left at zero.
+const DefaultMasterPort int32 = 29500
@@ -0,0 +1,53 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// RestartPolicy controls how the leader/worker controllers react to a
+// container restart within a group, intended as a field on
+// LeaderWorkerTemplate.
+type RestartPolicy string
+
+const (
+	// RestartPolicyRecreateGroupOnAnyRestart recreates the whole group
+	// whenever any container in it restarts. This is the pre-existing
+	// behavior and remains the default.
+	RestartPolicyRecreateGroupOnAnyRestart RestartPolicy = "RecreateGroupOnAnyRestart"
+	// RestartPolicyRecreateGroupOnLeaderRestart only recreates the group
+	// when the leader container restarts; worker restarts are left alone.
+	RestartPolicyRecreateGroupOnLeaderRestart RestartPolicy = "RecreateGroupOnLeaderRestart"
+	// RestartPolicyRecreateGroupOnFatalOnly recreates the group only when a
+	// restart is classified as fatal or has exceeded its restart budget.
+	RestartPolicyRecreateGroupOnFatalOnly RestartPolicy = "RecreateGroupOnFatalOnly"
+	// RestartPolicyNone never recreates the group due to a container
+	// restart; the kubelet's own container restart behavior still applies.
+	RestartPolicyNone RestartPolicy = "None"
+)
+
+// RestartBudget bounds how many restarts a container may accumulate before
+// a restart is considered outside budget, intended to be set per leader and
+// per worker on LeaderWorkerTemplate.
+type RestartBudget struct {
+	// MaxRestarts is the number of restarts allowed within WindowMinutes before a
+	// restart is reported as outside budget. Zero means unbounded.
+	// +optional
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+
+	// WindowMinutes is the rolling duration, in minutes, over which MaxRestarts is
+	// enforced.
+	// +optional
+	WindowMinutes int32 `json:"windowMinutes,omitempty"`
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SchedulingBackend names a gang-scheduler LWS can delegate group-atomic
+// scheduling to.
+type SchedulingBackend string
+
+const (
+	// SchedulingBackendVolcano names Volcano's scheduling.volcano.sh/v1beta1
+	// PodGroup. PodGroupReconciler does not reconcile this backend; it
+	// returns an error for it.
+	SchedulingBackendVolcano SchedulingBackend = "Volcano"
+	// SchedulingBackendKueue names Kueue's kueue.x-k8s.io Workload.
+	// PodGroupReconciler does not reconcile this backend; it returns an
+	// error for it.
+	SchedulingBackendKueue SchedulingBackend = "Kueue"
+	// SchedulingBackendCoscheduling reconciles a
+	// scheduling.x-k8s.io/PodGroup per group, as consumed by the Kubernetes
+	// scheduler-plugins coscheduling plugin.
+	SchedulingBackendCoscheduling SchedulingBackend = "Coscheduling"
+)
+
+// SchedulingPolicy configures gang scheduling so that a whole LWS group is
+// scheduled atomically or not at all, intended as a field on
+// LeaderWorkerSetSpec.
+type SchedulingPolicy struct {
+	// Backend selects which gang-scheduler integration reconciles the
+	// per-group scheduling object.
+	Backend SchedulingBackend `json:"backend"`
+}
+
+const (
+	// PodGroupNameLabelKey is stamped on every pod in a group with the name
+	// of the PodGroup/Workload CR representing it, in the form
+	// "<lwsName>-<groupIndex>".
+	PodGroupNameLabelKey = "scheduling.k8s.io/group-name"
+	// CoschedulingPodGroupNameLabelKey is the label key the
+	// scheduler-plugins coscheduling plugin reads to associate a pod with
+	// its scheduling.x-k8s.io/PodGroup.
+	CoschedulingPodGroupNameLabelKey = "pod-group.scheduling.sigs.k8s.io/name"
+)
+
+// LwsGangReady is the environment variable SetGangReadyEnvVar sets on every
+// container. The pod webhook stamps it "false" at admission, and since a
+// container's env cannot change after the pod is created, it stays "false";
+// the group's later gang-readiness is reported through the
+// leaderworkerset.sigs.k8s.io/gang-ready pod annotation instead.
+const LwsGangReady = "LWS_GANG_READY"
@@ -0,0 +1,184 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulingv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+)
+
+// PodGroupReconciler reconciles the gang-scheduling PodGroup/Workload CR for
+// each active group of an LWS that sets Spec.SchedulingPolicy, so that a
+// whole group is scheduled atomically or not at all.
+//
+// It does not reconcile LeaderWorkerSet objects directly; it watches pods so
+// that a group's scheduling object always reflects the group's current
+// membership and size.
+type PodGroupReconciler struct {
+	client.Client
+
+	// Backend is injected by the caller that wires up the manager, one
+	// reconciler per configured SchedulingBackend.
+	Backend leaderworkerset.SchedulingBackend
+}
+
+// Reconcile ensures the PodGroup/Workload named after req exists with
+// minMember set to the group's size, and patches podutils.
+// GangReadyAnnotationKey onto the group's pods, "true" once the backend
+// reports every member as scheduled and "false" until then. It does not touch the pods' LWS_GANG_READY env
+// var: Containers[].Env is immutable once a pod is created, so that value,
+// stamped once by the mutating webhook at admission time, can never be
+// updated from here.
+func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(req.Namespace), client.MatchingLabels{
+		leaderworkerset.PodGroupNameLabelKey: req.Name,
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(podList.Items) == 0 {
+		// The group has been torn down; nothing left to reconcile.
+		return ctrl.Result{}, nil
+	}
+
+	groupSize, err := expectedGroupSize(podList.Items[0])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ready, err := r.reconcilePodGroup(ctx, req.Namespace, req.Name, groupSize, len(podList.Items))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	want := strconv.FormatBool(ready)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Annotations[podutils.GangReadyAnnotationKey] == want {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		podutils.SetGangReadyAnnotation(pod, ready)
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("patching gang-ready annotation on pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePodGroup is a seam for the backend-specific CR and returns whether
+// the backend currently reports the group as fully scheduled. Only the
+// Coscheduling backend is reconciled; Volcano and Kueue return an error.
+func (r *PodGroupReconciler) reconcilePodGroup(ctx context.Context, namespace, name string, minMember, currentMember int) (bool, error) {
+	switch r.Backend {
+	case leaderworkerset.SchedulingBackendCoscheduling:
+		return r.reconcileCoschedulingPodGroup(ctx, namespace, name, minMember)
+	case leaderworkerset.SchedulingBackendVolcano, leaderworkerset.SchedulingBackendKueue:
+		// Volcano's scheduling.volcano.sh/v1beta1.PodGroup and Kueue's
+		// Workload follow the same minMember/ready shape as the
+		// coscheduling PodGroup above; they are not reconciled here.
+		return false, fmt.Errorf("scheduling backend %s is not yet implemented", r.Backend)
+	default:
+		return false, fmt.Errorf("unknown scheduling backend %q", r.Backend)
+	}
+}
+
+// reconcileCoschedulingPodGroup ensures a scheduling.x-k8s.io/v1alpha1
+// PodGroup named name exists in namespace with Spec.MinMember set to
+// minMember, creating or updating it as needed, and reports whether the
+// scheduler-plugins coscheduling plugin has marked it PodGroupScheduled.
+func (r *PodGroupReconciler) reconcileCoschedulingPodGroup(ctx context.Context, namespace, name string, minMember int) (bool, error) {
+	var podGroup schedulingv1alpha1.PodGroup
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &podGroup)
+	switch {
+	case apierrors.IsNotFound(err):
+		podGroup = schedulingv1alpha1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       schedulingv1alpha1.PodGroupSpec{MinMember: int32(minMember)},
+		}
+		if err := r.Create(ctx, &podGroup); err != nil {
+			return false, fmt.Errorf("creating PodGroup %s/%s: %w", namespace, name, err)
+		}
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("getting PodGroup %s/%s: %w", namespace, name, err)
+	}
+
+	if podGroup.Spec.MinMember != int32(minMember) {
+		podGroup.Spec.MinMember = int32(minMember)
+		if err := r.Update(ctx, &podGroup); err != nil {
+			return false, fmt.Errorf("updating PodGroup %s/%s: %w", namespace, name, err)
+		}
+	}
+
+	return podGroup.Status.Phase == schedulingv1alpha1.PodGroupScheduled, nil
+}
+
+func expectedGroupSize(pod corev1.Pod) (int, error) {
+	size, found := pod.Labels[leaderworkerset.GroupSizeLabelKey]
+	if !found {
+		return 0, fmt.Errorf("no group size label found for pod %v", pod.Name)
+	}
+	var groupSize int
+	if _, err := fmt.Sscanf(size, "%d", &groupSize); err != nil {
+		return 0, fmt.Errorf("invalid group size %q for pod %v", size, pod.Name)
+	}
+	return groupSize, nil
+}
+
+// SetupWithManager wires the reconciler to watch pods carrying the
+// PodGroupNameLabelKey label, mapping each pod event to a reconcile request
+// keyed by that label's value rather than the triggering pod's own name, so
+// Reconcile's req.Name lines up with the group name it Lists pods by.
+func (r *PodGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("podgroup").
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(mapPodToGroupRequest)).
+		Complete(r)
+}
+
+// mapPodToGroupRequest maps a pod carrying PodGroupNameLabelKey to a
+// reconcile.Request for its group. Pods without the label (not yet admitted
+// through the mutating webhook, or not part of a gang-scheduled LWS) are
+// ignored.
+func mapPodToGroupRequest(ctx context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	groupName, found := pod.Labels[leaderworkerset.PodGroupNameLabelKey]
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: groupName}}}
+}
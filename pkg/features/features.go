@@ -0,0 +1,49 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates used to guard behavior that is
+// not yet stable enough to enable unconditionally.
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// PodIndexLabel enables emitting the pod-index and global-rank labels on
+	// group member pods, so that Services and PodMonitors can select a
+	// specific rank directly. The matching LWS_WORKER_INDEX and
+	// LWS_GLOBAL_RANK env vars are injected by AddLWSVariables whether or not
+	// this gate is enabled.
+	//
+	// owner: lws-maintainers
+	// alpha: v0.5
+	PodIndexLabel featuregate.Feature = "PodIndexLabel"
+)
+
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	PodIndexLabel: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// DefaultFeatureGate is the shared featuregate.FeatureGate used by the
+// manager, controllers and webhooks to decide whether an alpha behavior is
+// enabled.
+var DefaultFeatureGate = featuregate.NewFeatureGate()
+
+func init() {
+	runtime.Must(DefaultFeatureGate.Add(defaultFeatureGates))
+}
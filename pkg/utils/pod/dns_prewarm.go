@@ -0,0 +1,137 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// AddLeaderDNSPrewarmInitContainer injects an init container into pod, built
+// from cfg, that blocks until the pod's LWS_LEADER_ADDRESS environment
+// variable resolves and accepts a TCP connection on cfg.Port, then exits 0.
+// It must run after AddLWSVariables has populated that env var. It is a
+// no-op if pod already has an init container named DNSPrewarmContainerName, so it
+// is safe to call more than once against the same pod.
+func AddLeaderDNSPrewarmInitContainer(pod *corev1.Pod, cfg leaderworkerset.LeaderDNSPrewarmConfig) error {
+	leaderAddress := envVarValue(pod, leaderworkerset.LwsLeaderAddress)
+	if leaderAddress == "" {
+		return fmt.Errorf("failure injecting dns prewarm init container, %s not set on pod %v; call AddLWSVariables first", leaderworkerset.LwsLeaderAddress, pod.Name)
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == leaderworkerset.DNSPrewarmContainerName {
+			return nil
+		}
+	}
+
+	image := cfg.Image
+	if image == "" {
+		image = leaderworkerset.DefaultDNSPrewarmImage
+	}
+	timeout := cfg.TimeoutSeconds
+	if timeout == 0 {
+		timeout = 60
+	}
+	backoff := cfg.RetryBackoffSeconds
+	if backoff == 0 {
+		backoff = 2
+	}
+
+	initContainer := corev1.Container{
+		Name:  leaderworkerset.DNSPrewarmContainerName,
+		Image: image,
+		Env: []corev1.EnvVar{
+			{Name: "LWS_DNS_PREWARM_TARGET", Value: leaderAddress},
+			{Name: "LWS_DNS_PREWARM_PORT", Value: fmt.Sprintf("%d", cfg.Port)},
+			{Name: "LWS_DNS_PREWARM_TIMEOUT_SECONDS", Value: fmt.Sprintf("%d", timeout)},
+			{Name: "LWS_DNS_PREWARM_RETRY_BACKOFF_SECONDS", Value: fmt.Sprintf("%d", backoff)},
+		},
+	}
+
+	if cfg.WriteToEtcHosts {
+		initContainer.Env = append(initContainer.Env,
+			corev1.EnvVar{Name: "LWS_DNS_PREWARM_WRITE_ETC_HOSTS", Value: "true"},
+			// The image is expected to seed this file from the container's own
+			// /etc/hosts before appending the resolved leader entry, since
+			// mountEtcHostsVolume overmounts exactly this path onto every app
+			// container's /etc/hosts.
+			corev1.EnvVar{Name: "LWS_DNS_PREWARM_ETC_HOSTS_FILE", Value: "/etc/lws-dns-prewarm/" + etcHostsFileName},
+		)
+		mountEtcHostsVolume(pod, &initContainer)
+	}
+
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+	return nil
+}
+
+func envVarValue(pod *corev1.Pod, name string) string {
+	for _, c := range pod.Spec.Containers {
+		for _, e := range c.Env {
+			if e.Name == name {
+				return e.Value
+			}
+		}
+	}
+	return ""
+}
+
+// etcHostsFileName is the file the init container writes the resolved
+// leader IP into, inside the shared emptyDir, before it is overmounted onto
+// every application container's /etc/hosts.
+const etcHostsFileName = "hosts"
+
+// mountEtcHostsVolume wires a shared emptyDir between initContainer and
+// every application container so the prewarm init container can write a
+// resolved /etc/hosts and have it actually take effect for the app
+// containers. The init container gets the emptyDir mounted as a directory so
+// it can write etcHostsFileName into it; each app container gets just that
+// file overmounted at /etc/hosts via subPath, the same technique the kubelet
+// itself uses for hostAliases, so libraries that cache DNS at process start
+// see the resolved address without the app needing to know about the shared
+// volume.
+func mountEtcHostsVolume(pod *corev1.Pod, initContainer *corev1.Container) {
+	found := false
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == leaderworkerset.DNSPrewarmVolumeName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         leaderworkerset.DNSPrewarmVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	initContainer.VolumeMounts = append(initContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      leaderworkerset.DNSPrewarmVolumeName,
+		MountPath: "/etc/lws-dns-prewarm",
+	})
+	appMount := corev1.VolumeMount{
+		Name:      leaderworkerset.DNSPrewarmVolumeName,
+		MountPath: "/etc/hosts",
+		SubPath:   etcHostsFileName,
+	}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, appMount)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestAddLeaderDNSPrewarmInitContainerRequiresLeaderAddress(t *testing.T) {
+	pod := basePod()
+	if err := AddLeaderDNSPrewarmInitContainer(pod, leaderworkerset.LeaderDNSPrewarmConfig{Port: 8080}); err == nil {
+		t.Error("expected error when LWS_LEADER_ADDRESS has not been set yet")
+	}
+}
+
+func TestAddLeaderDNSPrewarmInitContainerDefaults(t *testing.T) {
+	pod := basePod()
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	if err := AddLeaderDNSPrewarmInitContainer(pod, leaderworkerset.LeaderDNSPrewarmConfig{Port: 8080}); err != nil {
+		t.Fatalf("AddLeaderDNSPrewarmInitContainer returned error: %v", err)
+	}
+
+	if len(pod.Spec.InitContainers) != 1 {
+		t.Fatalf("got %d init containers, want 1", len(pod.Spec.InitContainers))
+	}
+	c := pod.Spec.InitContainers[0]
+	if c.Name != leaderworkerset.DNSPrewarmContainerName {
+		t.Errorf("init container name = %q, want %q", c.Name, leaderworkerset.DNSPrewarmContainerName)
+	}
+	if c.Image != leaderworkerset.DefaultDNSPrewarmImage {
+		t.Errorf("init container image = %q, want default %q", c.Image, leaderworkerset.DefaultDNSPrewarmImage)
+	}
+	if v, _ := envValue(c, "LWS_DNS_PREWARM_TARGET"); v != "sts-1.sts.default" {
+		t.Errorf("LWS_DNS_PREWARM_TARGET = %q, want leader address", v)
+	}
+	if v, _ := envValue(c, "LWS_DNS_PREWARM_TIMEOUT_SECONDS"); v != "60" {
+		t.Errorf("LWS_DNS_PREWARM_TIMEOUT_SECONDS = %q, want default 60", v)
+	}
+}
+
+func TestAddLeaderDNSPrewarmInitContainerIdempotent(t *testing.T) {
+	pod := basePod()
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+	cfg := leaderworkerset.LeaderDNSPrewarmConfig{Port: 8080}
+	if err := AddLeaderDNSPrewarmInitContainer(pod, cfg); err != nil {
+		t.Fatalf("AddLeaderDNSPrewarmInitContainer returned error: %v", err)
+	}
+	if err := AddLeaderDNSPrewarmInitContainer(pod, cfg); err != nil {
+		t.Fatalf("second AddLeaderDNSPrewarmInitContainer call returned error: %v", err)
+	}
+	if len(pod.Spec.InitContainers) != 1 {
+		t.Errorf("got %d init containers after calling twice, want 1", len(pod.Spec.InitContainers))
+	}
+}
+
+func TestAddLeaderDNSPrewarmInitContainerWriteToEtcHosts(t *testing.T) {
+	pod := basePod()
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	cfg := leaderworkerset.LeaderDNSPrewarmConfig{Port: 8080, WriteToEtcHosts: true}
+	if err := AddLeaderDNSPrewarmInitContainer(pod, cfg); err != nil {
+		t.Fatalf("AddLeaderDNSPrewarmInitContainer returned error: %v", err)
+	}
+
+	init := pod.Spec.InitContainers[0]
+	if v, found := envValue(init, "LWS_DNS_PREWARM_WRITE_ETC_HOSTS"); !found || v != "true" {
+		t.Errorf("LWS_DNS_PREWARM_WRITE_ETC_HOSTS = %q, found=%v, want \"true\"", v, found)
+	}
+
+	foundVolume := false
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == leaderworkerset.DNSPrewarmVolumeName {
+			foundVolume = true
+		}
+	}
+	if !foundVolume {
+		t.Error("expected shared emptyDir volume to be added")
+	}
+
+	app := pod.Spec.Containers[0]
+	foundMount := false
+	for _, m := range app.VolumeMounts {
+		if m.Name == leaderworkerset.DNSPrewarmVolumeName && m.MountPath == "/etc/hosts" && m.SubPath == etcHostsFileName {
+			foundMount = true
+		}
+	}
+	if !foundMount {
+		t.Error("expected app container to overmount /etc/hosts via subPath")
+	}
+}
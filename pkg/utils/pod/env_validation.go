@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// reservedEnvVarPrefix is the namespace of environment variables
+// AddLWSVariables manages; addEnvVarIfNotExists silently declines to
+// overwrite a user-supplied value in this namespace, which hides
+// misconfiguration rather than surfacing it.
+const reservedEnvVarPrefix = "LWS_"
+
+// allowEnvOverrideAnnotationKey, when set to "true" on the LeaderWorkerSet,
+// lets users declare env vars in the reserved LWS_* namespace without being
+// rejected by ValidateNoReservedEnvVars.
+const allowEnvOverrideAnnotationKey = "leaderworkerset.sigs.k8s.io/allow-env-override"
+
+// ValidateNoReservedEnvVars checks every container and init container in
+// podSpec for a user-declared environment variable in the reserved LWS_*
+// namespace, returning one field.Error per offending (container, env name)
+// pair. Callers that allow an override annotation on the owning
+// LeaderWorkerSet should skip calling this rather than passing allowOverride,
+// so the check composes the same way whether it's invoked from the
+// validating webhook or by a downstream controller's own linting.
+func ValidateNoReservedEnvVars(podSpec corev1.PodSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateContainersNoReservedEnvVars(podSpec.Containers, fldPath.Child("containers"))...)
+	allErrs = append(allErrs, validateContainersNoReservedEnvVars(podSpec.InitContainers, fldPath.Child("initContainers"))...)
+	return allErrs
+}
+
+func validateContainersNoReservedEnvVars(containers []corev1.Container, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, c := range containers {
+		for j, e := range c.Env {
+			if strings.HasPrefix(e.Name, reservedEnvVarPrefix) {
+				allErrs = append(allErrs, field.Forbidden(
+					fldPath.Index(i).Child("env").Index(j).Child("name"),
+					fmt.Sprintf("container %q must not set reserved environment variable %q; set annotation %q=true on the LeaderWorkerSet to allow this", c.Name, e.Name, allowEnvOverrideAnnotationKey),
+				))
+			}
+		}
+	}
+	return allErrs
+}
+
+// AllowsEnvOverride reports whether lwsAnnotations carries the
+// allow-env-override annotation set to "true", in which case the validating
+// webhook should skip ValidateNoReservedEnvVars entirely for pods belonging
+// to that LeaderWorkerSet.
+func AllowsEnvOverride(lwsAnnotations map[string]string) bool {
+	return lwsAnnotations[allowEnvOverrideAnnotationKey] == "true"
+}
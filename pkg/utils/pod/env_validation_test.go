@@ -0,0 +1,83 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateNoReservedEnvVarsRejectsReservedPrefix(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "main", Env: []corev1.EnvVar{{Name: "LWS_LEADER_ADDRESS", Value: "user-set"}}},
+		},
+	}
+
+	errs := ValidateNoReservedEnvVars(spec, field.NewPath("spec"))
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+}
+
+func TestValidateNoReservedEnvVarsChecksInitContainers(t *testing.T) {
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "init", Env: []corev1.EnvVar{{Name: "LWS_GROUP_SIZE", Value: "4"}}},
+		},
+	}
+
+	errs := ValidateNoReservedEnvVars(spec, field.NewPath("spec"))
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+}
+
+func TestValidateNoReservedEnvVarsAllowsUnreservedNames(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "main", Env: []corev1.EnvVar{{Name: "MY_APP_CONFIG", Value: "1"}}},
+		},
+	}
+
+	if errs := ValidateNoReservedEnvVars(spec, field.NewPath("spec")); len(errs) != 0 {
+		t.Errorf("got %d errors for a non-reserved env var name, want 0", len(errs))
+	}
+}
+
+func TestAllowsEnvOverride(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"unset":     {annotations: nil, want: false},
+		"false":     {annotations: map[string]string{"leaderworkerset.sigs.k8s.io/allow-env-override": "false"}, want: false},
+		"true":      {annotations: map[string]string{"leaderworkerset.sigs.k8s.io/allow-env-override": "true"}, want: true},
+		"other key": {annotations: map[string]string{"some-other-annotation": "true"}, want: false},
+		"empty map": {annotations: map[string]string{}, want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := AllowsEnvOverride(tc.annotations); got != tc.want {
+				t.Errorf("AllowsEnvOverride(%v) = %v, want %v", tc.annotations, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// PodGroupName returns the name the gang-scheduling PodGroup/Workload CR for
+// pod's group is reconciled under, "<lwsName>-<groupIndex>".
+func PodGroupName(pod corev1.Pod) (string, error) {
+	lwsName, found := pod.Labels[leaderworkerset.SetNameLabelKey]
+	if !found {
+		return "", fmt.Errorf("failure constructing pod group name, no name label found for pod %v", pod.Name)
+	}
+	groupIndex, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]
+	if !found {
+		return "", fmt.Errorf("failure constructing pod group name, no group index label found for pod %v", pod.Name)
+	}
+	return fmt.Sprintf("%s-%s", lwsName, groupIndex), nil
+}
+
+// AddGangSchedulingLabels stamps pod with the group-name labels the
+// configured scheduling backend(s) key their gang-scheduling objects on. It
+// is called from the mutating pod webhook, next to AddLWSVariables, whenever
+// the owning LeaderWorkerSet's Spec.SchedulingPolicy is set; without it, no
+// pod ever carries PodGroupNameLabelKey, so PodGroupReconciler's watch never
+// fires for it.
+func AddGangSchedulingLabels(pod *corev1.Pod, backend leaderworkerset.SchedulingBackend) error {
+	name, err := PodGroupName(*pod)
+	if err != nil {
+		return err
+	}
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[leaderworkerset.PodGroupNameLabelKey] = name
+	if backend == leaderworkerset.SchedulingBackendCoscheduling {
+		pod.Labels[leaderworkerset.CoschedulingPodGroupNameLabelKey] = name
+	}
+	return nil
+}
+
+// SetGangReadyEnvVar sets LWS_GANG_READY to "true" or "false" on every
+// container in pod. Because Containers[].Env is immutable once a pod is
+// created, this only has an effect when called from the mutating webhook at
+// admission time, and the value it stamps there can never change afterward.
+// Callers that need to observe gang readiness over the pod's lifetime, such
+// as PodGroupReconciler, must use GangReadyAnnotationKey/
+// SetGangReadyAnnotation instead.
+func SetGangReadyEnvVar(pod *corev1.Pod, ready bool) {
+	e := corev1.EnvVar{Name: leaderworkerset.LwsGangReady, Value: fmt.Sprintf("%t", ready)}
+	for i := range pod.Spec.Containers {
+		addEnvVarIfNotExists(&pod.Spec.Containers[i], e)
+	}
+	for i := range pod.Spec.InitContainers {
+		addEnvVarIfNotExists(&pod.Spec.InitContainers[i], e)
+	}
+}
+
+// GangReadyAnnotationKey is patched onto every pod in a group by
+// PodGroupReconciler: "true" while the group's gang-scheduling object reports
+// every member as scheduled, "false" otherwise, including when the group
+// later stops being fully scheduled. Unlike LWS_GANG_READY, a pod's annotations can be updated
+// after creation, so this is the signal that actually reflects the group's
+// current gang-readiness rather than only its state at admission time.
+const GangReadyAnnotationKey = "leaderworkerset.sigs.k8s.io/gang-ready"
+
+// SetGangReadyAnnotation sets or clears GangReadyAnnotationKey on pod to
+// reflect ready. Callers are responsible for persisting the mutated pod,
+// typically via a Patch against the original.
+func SetGangReadyAnnotation(pod *corev1.Pod, ready bool) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[GangReadyAnnotationKey] = fmt.Sprintf("%t", ready)
+}
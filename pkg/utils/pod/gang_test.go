@@ -0,0 +1,93 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestPodGroupName(t *testing.T) {
+	pod := basePod()
+	name, err := PodGroupName(*pod)
+	if err != nil {
+		t.Fatalf("PodGroupName returned error: %v", err)
+	}
+	if want := "sts-1"; name != want {
+		t.Errorf("PodGroupName = %q, want %q", name, want)
+	}
+}
+
+func TestPodGroupNameMissingLabels(t *testing.T) {
+	pod := basePod()
+	delete(pod.Labels, leaderworkerset.GroupIndexLabelKey)
+	if _, err := PodGroupName(*pod); err == nil {
+		t.Error("expected error for missing group index label, got nil")
+	}
+}
+
+func TestAddGangSchedulingLabels(t *testing.T) {
+	pod := basePod()
+	if err := AddGangSchedulingLabels(pod, leaderworkerset.SchedulingBackendCoscheduling); err != nil {
+		t.Fatalf("AddGangSchedulingLabels returned error: %v", err)
+	}
+	if got := pod.Labels[leaderworkerset.PodGroupNameLabelKey]; got != "sts-1" {
+		t.Errorf("PodGroupNameLabelKey = %q, want \"sts-1\"", got)
+	}
+	if got := pod.Labels[leaderworkerset.CoschedulingPodGroupNameLabelKey]; got != "sts-1" {
+		t.Errorf("CoschedulingPodGroupNameLabelKey = %q, want \"sts-1\" for the Coscheduling backend", got)
+	}
+}
+
+func TestAddGangSchedulingLabelsOtherBackendOmitsCoschedulingLabel(t *testing.T) {
+	pod := basePod()
+	if err := AddGangSchedulingLabels(pod, leaderworkerset.SchedulingBackendVolcano); err != nil {
+		t.Fatalf("AddGangSchedulingLabels returned error: %v", err)
+	}
+	if _, found := pod.Labels[leaderworkerset.CoschedulingPodGroupNameLabelKey]; found {
+		t.Error("CoschedulingPodGroupNameLabelKey stamped for a non-Coscheduling backend")
+	}
+}
+
+func TestSetGangReadyEnvVar(t *testing.T) {
+	pod := basePod()
+	SetGangReadyEnvVar(pod, false)
+	if v, found := envValue(pod.Spec.Containers[0], leaderworkerset.LwsGangReady); !found || v != "false" {
+		t.Errorf("LWS_GANG_READY = %q, found=%v, want \"false\"", v, found)
+	}
+
+	// Env vars are set once, at admission; a later call with a different
+	// value must not overwrite what's already there.
+	SetGangReadyEnvVar(pod, true)
+	if v, _ := envValue(pod.Spec.Containers[0], leaderworkerset.LwsGangReady); v != "false" {
+		t.Errorf("LWS_GANG_READY = %q after second call, want unchanged \"false\"", v)
+	}
+}
+
+func TestSetGangReadyAnnotation(t *testing.T) {
+	pod := basePod()
+	SetGangReadyAnnotation(pod, true)
+	if got := pod.Annotations[GangReadyAnnotationKey]; got != "true" {
+		t.Errorf("GangReadyAnnotationKey = %q, want \"true\"", got)
+	}
+
+	SetGangReadyAnnotation(pod, false)
+	if got := pod.Annotations[GangReadyAnnotationKey]; got != "false" {
+		t.Errorf("GangReadyAnnotationKey = %q after flipping to not-ready, want \"false\"", got)
+	}
+}
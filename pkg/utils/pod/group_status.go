@@ -0,0 +1,155 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// GroupSummary is the structured, group-level readiness summary returned by
+// AggregateGroupStatus, replacing ad hoc per-pod ready counting in callers
+// such as HPA metrics adapters or Kueue integrations.
+type GroupSummary struct {
+	// LeaderPhase is the leader pod's current phase, or "" if no leader pod
+	// was found in the group.
+	LeaderPhase corev1.PodPhase
+
+	// ReadyWorkers is the number of non-leader pods that are running and ready.
+	ReadyWorkers int
+
+	// WorkerReadiness is a bitmap of worker readiness ordered by
+	// WorkerIndexLabelKey, excluding the leader at index 0.
+	WorkerReadiness []bool
+
+	// OldestNotReadyDuration is how long the longest-waiting not-ready pod
+	// in the group has been not ready, or 0 if every pod is ready.
+	OldestNotReadyDuration time.Duration
+
+	// Condition mirrors getPodReadyCondition but summarizes the whole group.
+	Condition GroupConditionStatus
+}
+
+// GroupConditionStatus is the group-level analogue of corev1.PodCondition,
+// covering the three conditions GroupStatus.Conditions is expected to carry,
+// plus NotReady when none of them holds.
+type GroupConditionStatus string
+
+const (
+	GroupConditionAllMembersReady GroupConditionStatus = "AllMembersReady"
+	GroupConditionLeaderReady     GroupConditionStatus = "LeaderReady"
+	GroupConditionPartiallyReady  GroupConditionStatus = "PartiallyReady"
+	GroupConditionNotReady        GroupConditionStatus = "NotReady"
+)
+
+// AggregateGroupStatus summarizes the readiness of every pod belonging to
+// one group. pods must all share the same GroupIndexLabelKey value; callers
+// typically obtain this slice via a label selector on that key. now is
+// passed in, rather than read from time.Now, so callers can compute
+// OldestNotReadyDuration deterministically in tests.
+func AggregateGroupStatus(pods []corev1.Pod, now time.Time) GroupSummary {
+	ordered := make([]corev1.Pod, len(pods))
+	copy(ordered, pods)
+	sort.Slice(ordered, func(i, j int) bool {
+		return workerIndexOf(ordered[i]) < workerIndexOf(ordered[j])
+	})
+
+	var summary GroupSummary
+	var oldestNotReady *time.Time
+
+	for _, p := range ordered {
+		ready := PodRunningAndReady(p)
+		if LeaderPod(p) {
+			summary.LeaderPhase = p.Status.Phase
+		} else {
+			summary.WorkerReadiness = append(summary.WorkerReadiness, ready)
+			if ready {
+				summary.ReadyWorkers++
+			}
+		}
+		if !ready {
+			// A pod whose Ready condition has turned false has been not ready
+			// since that transition; one without it, since creation.
+			since := p.CreationTimestamp.Time
+			if c := getPodReadyCondition(p.Status); c != nil && c.Status != corev1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+				since = c.LastTransitionTime.Time
+			}
+			if oldestNotReady == nil || since.Before(*oldestNotReady) {
+				oldestNotReady = &since
+			}
+		}
+	}
+
+	if oldestNotReady != nil {
+		summary.OldestNotReadyDuration = now.Sub(*oldestNotReady)
+	}
+
+	summary.Condition = groupCondition(ordered, summary)
+	return summary
+}
+
+// workerIndexOf parses pod's WorkerIndexLabelKey as an integer so pods sort
+// numerically (0, 1, 2, ..., 10, 11) rather than lexicographically
+// ("0", "1", "10", "11", ..., "2"), which matters for any group of 10 or
+// more workers. Pods with a missing or malformed label sort last.
+func workerIndexOf(pod corev1.Pod) int {
+	index, err := strconv.Atoi(pod.Labels[leaderworkerset.WorkerIndexLabelKey])
+	if err != nil {
+		return math.MaxInt
+	}
+	return index
+}
+
+func groupCondition(pods []corev1.Pod, summary GroupSummary) GroupConditionStatus {
+	leaderReady := false
+	for _, p := range pods {
+		if LeaderPod(p) && PodRunningAndReady(p) {
+			leaderReady = true
+		}
+	}
+
+	// anyReady/allReady must be seeded from leaderReady, not from
+	// summary.LeaderPhase: a leader that is Running but failing its
+	// readiness probe is not a "ready" member, and with no worker ready
+	// either the group must report NotReady rather than PartiallyReady.
+	allReady := leaderReady
+	anyReady := leaderReady
+	for _, ready := range summary.WorkerReadiness {
+		if ready {
+			anyReady = true
+		} else {
+			allReady = false
+		}
+	}
+
+	switch {
+	case allReady && leaderReady:
+		return GroupConditionAllMembersReady
+	case leaderReady:
+		return GroupConditionLeaderReady
+	case anyReady:
+		return GroupConditionPartiallyReady
+	default:
+		return GroupConditionNotReady
+	}
+}
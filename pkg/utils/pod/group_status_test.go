@@ -0,0 +1,107 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func readyPod(workerIndex int) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("worker-%d", workerIndex),
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: fmt.Sprintf("%d", workerIndex),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+// TestAggregateGroupStatusOrdersWorkersNumerically guards against a
+// regression where WorkerIndexLabelKey, a string label, was sorted
+// lexicographically: worker "10" would sort before worker "2".
+func TestAggregateGroupStatusOrdersWorkersNumerically(t *testing.T) {
+	leader := readyPod(0)
+	leader.Status.Conditions[0].Status = corev1.ConditionFalse // leader not ready, workers still ordered below
+
+	pods := []corev1.Pod{leader}
+	// Insert workers out of order and include indices >= 10 so a
+	// string-based sort would misplace them.
+	for _, i := range []int{11, 1, 10, 2, 3} {
+		p := readyPod(i)
+		if i == 3 {
+			p.Status.Conditions[0].Status = corev1.ConditionFalse
+		}
+		pods = append(pods, p)
+	}
+
+	summary := AggregateGroupStatus(pods, time.Now())
+
+	want := []bool{true, true, false, true, true} // workers 1,2,3,10,11 in that numeric order
+	if len(summary.WorkerReadiness) != len(want) {
+		t.Fatalf("got %d worker readiness entries, want %d", len(summary.WorkerReadiness), len(want))
+	}
+	for i, ready := range want {
+		if summary.WorkerReadiness[i] != ready {
+			t.Errorf("WorkerReadiness[%d] = %v, want %v (order must be numeric, not lexicographic)", i, summary.WorkerReadiness[i], ready)
+		}
+	}
+}
+
+// TestAggregateGroupStatusLeaderRunningNotReady guards against a regression
+// where groupCondition seeded anyReady from the leader's raw phase instead
+// of its readiness: a Running-but-not-ready leader with no ready worker must
+// report NotReady, not PartiallyReady.
+func TestAggregateGroupStatusLeaderRunningNotReady(t *testing.T) {
+	leader := readyPod(0)
+	leader.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	worker := readyPod(1)
+	worker.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	summary := AggregateGroupStatus([]corev1.Pod{leader, worker}, time.Now())
+
+	if summary.Condition != GroupConditionNotReady {
+		t.Errorf("Condition = %v, want %v", summary.Condition, GroupConditionNotReady)
+	}
+}
+
+func TestAggregateGroupStatusOldestNotReadyDuration(t *testing.T) {
+	now := time.Now()
+	p := readyPod(1)
+	p.Status.Conditions[0].Status = corev1.ConditionFalse
+	p.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Minute))
+
+	summary := AggregateGroupStatus([]corev1.Pod{readyPod(0), p}, now)
+
+	if summary.OldestNotReadyDuration < 5*time.Minute {
+		t.Errorf("OldestNotReadyDuration = %v, want >= 5m", summary.OldestNotReadyDuration)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+)
+
+// AddPodIndexLabels stamps PodIndexLabelKey and GlobalRankLabelKey on pod,
+// derived from the already-present WorkerIndexLabelKey, GroupIndexLabelKey
+// and GroupSizeLabelKey labels. It is called from the mutating pod webhook,
+// gated by the PodIndexLabel feature: if the feature is disabled this is a
+// no-op rather than an error, so operators who haven't opted in see no
+// behavior change. When enabled, it returns an error rather than silently
+// leaving the labels unset so callers can fail the admission request
+// instead of shipping a pod with an inconsistent rank.
+func AddPodIndexLabels(pod *corev1.Pod) error {
+	if !features.DefaultFeatureGate.Enabled(features.PodIndexLabel) {
+		return nil
+	}
+
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return fmt.Errorf("failure constructing pod index labels, no worker index label found for pod %v", pod.Name)
+	}
+	groupIndex, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]
+	if !found {
+		return fmt.Errorf("failure constructing pod index labels, no group index label found for pod %v", pod.Name)
+	}
+	groupSize, found := pod.Labels[leaderworkerset.GroupSizeLabelKey]
+	if !found {
+		return fmt.Errorf("failure constructing pod index labels, no group size label found for pod %v", pod.Name)
+	}
+
+	workerIndexInt, err := strconv.Atoi(workerIndex)
+	if err != nil {
+		return fmt.Errorf("failure constructing pod index labels, invalid worker index %q for pod %v", workerIndex, pod.Name)
+	}
+	groupIndexInt, err := strconv.Atoi(groupIndex)
+	if err != nil {
+		return fmt.Errorf("failure constructing pod index labels, invalid group index %q for pod %v", groupIndex, pod.Name)
+	}
+	groupSizeInt, err := strconv.Atoi(groupSize)
+	if err != nil {
+		return fmt.Errorf("failure constructing pod index labels, invalid group size %q for pod %v", groupSize, pod.Name)
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[leaderworkerset.PodIndexLabelKey] = workerIndex
+	pod.Labels[leaderworkerset.GlobalRankLabelKey] = strconv.Itoa(groupIndexInt*groupSizeInt + workerIndexInt)
+
+	return nil
+}
+
+// ValidatePodIndexLabelsUnset returns an error if the user has already set
+// PodIndexLabelKey or GlobalRankLabelKey on pod, so the validating webhook
+// can reject the request instead of letting the controller silently
+// overwrite a user-supplied value. It is a no-op while the PodIndexLabel
+// feature is disabled, since the labels aren't managed by the controller in
+// that case and rejecting them would break existing users of the same label
+// names.
+func ValidatePodIndexLabelsUnset(pod corev1.Pod) error {
+	if !features.DefaultFeatureGate.Enabled(features.PodIndexLabel) {
+		return nil
+	}
+
+	for _, key := range []string{leaderworkerset.PodIndexLabelKey, leaderworkerset.GlobalRankLabelKey} {
+		if _, found := pod.Labels[key]; found {
+			return fmt.Errorf("pod %v must not set reserved label %q, it is managed by the LWS controller", pod.Name, key)
+		}
+	}
+	return nil
+}
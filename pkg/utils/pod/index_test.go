@@ -0,0 +1,92 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+)
+
+func indexedPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sts-1-2",
+			Labels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "2",
+				leaderworkerset.GroupSizeLabelKey:   "4",
+			},
+		},
+	}
+}
+
+// TestAddPodIndexLabelsFeatureGated guards against the gate being decorative:
+// with PodIndexLabel disabled (the default), AddPodIndexLabels must not
+// stamp the labels, and ValidatePodIndexLabelsUnset must not reject a user
+// who happens to set them.
+func TestAddPodIndexLabelsFeatureGated(t *testing.T) {
+	if features.DefaultFeatureGate.Enabled(features.PodIndexLabel) {
+		t.Fatal("expected PodIndexLabel to default to disabled")
+	}
+
+	pod := indexedPod()
+	if err := AddPodIndexLabels(pod); err != nil {
+		t.Fatalf("AddPodIndexLabels returned error while gate disabled: %v", err)
+	}
+	if _, found := pod.Labels[leaderworkerset.PodIndexLabelKey]; found {
+		t.Error("PodIndexLabelKey stamped while feature gate disabled")
+	}
+
+	userSet := indexedPod()
+	userSet.Labels[leaderworkerset.PodIndexLabelKey] = "99"
+	if err := ValidatePodIndexLabelsUnset(*userSet); err != nil {
+		t.Errorf("ValidatePodIndexLabelsUnset rejected user-supplied label while gate disabled: %v", err)
+	}
+}
+
+func TestAddPodIndexLabelsEnabled(t *testing.T) {
+	if err := features.DefaultFeatureGate.SetFromMap(map[string]bool{string(features.PodIndexLabel): true}); err != nil {
+		t.Fatalf("failed to enable PodIndexLabel: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := features.DefaultFeatureGate.SetFromMap(map[string]bool{string(features.PodIndexLabel): false}); err != nil {
+			t.Fatalf("failed to restore PodIndexLabel: %v", err)
+		}
+	})
+
+	pod := indexedPod()
+	if err := AddPodIndexLabels(pod); err != nil {
+		t.Fatalf("AddPodIndexLabels returned error: %v", err)
+	}
+	if got := pod.Labels[leaderworkerset.PodIndexLabelKey]; got != "2" {
+		t.Errorf("PodIndexLabelKey = %q, want \"2\"", got)
+	}
+	if got := pod.Labels[leaderworkerset.GlobalRankLabelKey]; got != "6" {
+		t.Errorf("GlobalRankLabelKey = %q, want \"6\"", got)
+	}
+
+	userSet := indexedPod()
+	userSet.Labels[leaderworkerset.PodIndexLabelKey] = "99"
+	if err := ValidatePodIndexLabelsUnset(*userSet); err == nil {
+		t.Error("expected ValidatePodIndexLabelsUnset to reject user-supplied label while gate enabled")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// OperationType describes the kind of disruptive action a controller is
+// about to perform on an LWS group member, stamped on the pod by
+// StampOperationStart so the pod carries its own handshake state.
+type OperationType string
+
+const (
+	OperationTypeUpgrade OperationType = "upgrade"
+	OperationTypeRestart OperationType = "restart"
+	OperationTypeScaleIn OperationType = "scale-in"
+	OperationTypeMigrate OperationType = "migration"
+)
+
+const (
+	// operatingLabelPrefix is stamped with the time the operation started,
+	// keyed by operation ID, so a checker can tell a new operation apart
+	// from one it has already processed.
+	operatingLabelPrefix = "operating.lws.x-k8s.io/"
+	// operationTypeLabelPrefix records which OperationType the operation ID
+	// corresponds to.
+	operationTypeLabelPrefix = "operation-type.lws.x-k8s.io/"
+	// preCheckLabelPrefix is stamped by the webhook to request that
+	// registered checkers run their pre-check before the operation proceeds.
+	preCheckLabelPrefix = "pre-check.lifecycle.lws.x-k8s.io/"
+	// preCheckCheckersLabelPrefix records the checkerNameSeparator-joined set
+	// of checker names that must each acknowledge opID before ReadyForOperation
+	// reports true, so that registering two or more checkers for the same
+	// operation can't be satisfied by the first one to respond.
+	preCheckCheckersLabelPrefix = "pre-check-checkers.lifecycle.lws.x-k8s.io/"
+	// preCheckedLabelPrefix is stamped by a checker once its pre-check has
+	// passed. It is keyed by both opID and checker name: ReadyForOperation
+	// reports true only once every checker named in
+	// preCheckCheckersLabelPrefix has set its own "<opID>.<checkerName>"
+	// label to "true".
+	preCheckedLabelPrefix = "pre-checked.lifecycle.lws.x-k8s.io/"
+	// postCheckLabelPrefix is stamped once the operation has been performed,
+	// mirroring preCheckLabelPrefix for the post-operation handshake.
+	postCheckLabelPrefix = "post-check.lifecycle.lws.x-k8s.io/"
+	// postCheckCheckersLabelPrefix is the post-check analogue of
+	// preCheckCheckersLabelPrefix.
+	postCheckCheckersLabelPrefix = "post-check-checkers.lifecycle.lws.x-k8s.io/"
+	// postCheckedLabelPrefix is stamped by a checker once its post-check has
+	// passed, keyed by opID and checker name like preCheckedLabelPrefix.
+	postCheckedLabelPrefix = "post-checked.lifecycle.lws.x-k8s.io/"
+
+	labelValueTrue = "true"
+	// checkerNameSeparator joins the registered checker names stamped in
+	// preCheckCheckersLabelPrefix/postCheckCheckersLabelPrefix. Kubernetes
+	// label values may not contain commas, so checker names are joined with
+	// "_" instead.
+	checkerNameSeparator = "_"
+)
+
+// StampOperationStart marks pod as the target of a new operation, recording
+// when it started and its OperationType under opID. The pod webhook's Default
+// does not call it; callers persist the mutated pod themselves.
+func StampOperationStart(pod *corev1.Pod, opID string, opType OperationType, startedAt string) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[operatingLabelPrefix+opID] = startedAt
+	pod.Labels[operationTypeLabelPrefix+opID] = string(opType)
+}
+
+// RequestPreCheck stamps pod so that every checker named in checkerNames
+// knows it must run its pre-check for opID before the operation is allowed
+// to proceed. checkerNames becomes the registered-checker set consulted by
+// PreCheckPassed.
+func RequestPreCheck(pod *corev1.Pod, opID string, requestedAt string, checkerNames []string) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[preCheckLabelPrefix+opID] = requestedAt
+	pod.Labels[preCheckCheckersLabelPrefix+opID] = strings.Join(checkerNames, checkerNameSeparator)
+}
+
+// AckPreCheck is called by checkerName once its pre-check for opID has
+// passed.
+func AckPreCheck(pod *corev1.Pod, opID, checkerName string) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[preCheckedLabelPrefix+opID+"."+checkerName] = labelValueTrue
+}
+
+// PreCheckPassed reports whether every checker registered via
+// RequestPreCheck for opID has acknowledged its pre-check with AckPreCheck.
+// A pre-check that was requested with no registered checkers is considered
+// passed immediately.
+func PreCheckPassed(pod corev1.Pod, opID string) bool {
+	return allCheckersAcked(pod, preCheckCheckersLabelPrefix+opID, preCheckedLabelPrefix+opID)
+}
+
+// RequestPostCheck stamps pod so that every checker named in checkerNames
+// knows it must run its post-check for opID now that the operation has been
+// performed.
+func RequestPostCheck(pod *corev1.Pod, opID string, requestedAt string, checkerNames []string) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[postCheckLabelPrefix+opID] = requestedAt
+	pod.Labels[postCheckCheckersLabelPrefix+opID] = strings.Join(checkerNames, checkerNameSeparator)
+}
+
+// AckPostCheck is called by checkerName once its post-check for opID has
+// passed.
+func AckPostCheck(pod *corev1.Pod, opID, checkerName string) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[postCheckedLabelPrefix+opID+"."+checkerName] = labelValueTrue
+}
+
+// PostCheckPassed reports whether every checker registered via
+// RequestPostCheck for opID has acknowledged its post-check with
+// AckPostCheck.
+func PostCheckPassed(pod corev1.Pod, opID string) bool {
+	return allCheckersAcked(pod, postCheckCheckersLabelPrefix+opID, postCheckedLabelPrefix+opID)
+}
+
+// allCheckersAcked reports whether every checker name stamped under
+// checkersLabelKey has a corresponding "<ackedLabelPrefix>.<checkerName>"
+// label set to "true". An absent or empty checker set is vacuously true.
+func allCheckersAcked(pod corev1.Pod, checkersLabelKey, ackedLabelPrefix string) bool {
+	checkers, found := pod.Labels[checkersLabelKey]
+	if !found || checkers == "" {
+		return true
+	}
+	for _, checker := range strings.Split(checkers, checkerNameSeparator) {
+		if pod.Labels[ackedLabelPrefix+"."+checker] != labelValueTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// OperationInProgress reports whether opID has been stamped on pod and has
+// not yet completed its post-check handshake.
+func OperationInProgress(pod corev1.Pod, opID string) bool {
+	_, operating := pod.Labels[operatingLabelPrefix+opID]
+	_, postCheckRequested := pod.Labels[postCheckLabelPrefix+opID]
+	return operating && (!postCheckRequested || !PostCheckPassed(pod, opID))
+}
+
+// ReadyForOperation reports whether the controller may perform the
+// disruptive action identified by opID against pod: either no pre-check was
+// requested, or every requested pre-check has passed.
+func ReadyForOperation(pod corev1.Pod, opID string) bool {
+	if _, requested := pod.Labels[preCheckLabelPrefix+opID]; !requested {
+		return true
+	}
+	return PreCheckPassed(pod, opID)
+}
+
+// OperationTypeOf returns the OperationType stamped for opID on pod, or an
+// error if the pod was never stamped for that operation.
+func OperationTypeOf(pod corev1.Pod, opID string) (OperationType, error) {
+	opType, found := pod.Labels[operationTypeLabelPrefix+opID]
+	if !found {
+		return "", fmt.Errorf("no operation-type label found for pod %s, operation %s", pod.Name, opID)
+	}
+	return OperationType(opType), nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestPreCheckPassedRequiresEveryRegisteredChecker guards against a
+// regression where a single shared "pre-checked" label let any one of
+// several registered checkers satisfy the whole gate.
+func TestPreCheckPassedRequiresEveryRegisteredChecker(t *testing.T) {
+	pod := &corev1.Pod{}
+	RequestPreCheck(pod, "op-1", "t0", []string{"drain-checker", "checkpoint-checker"})
+
+	if ReadyForOperation(*pod, "op-1") {
+		t.Fatal("expected not ready before any checker acks")
+	}
+
+	AckPreCheck(pod, "op-1", "drain-checker")
+	if ReadyForOperation(*pod, "op-1") {
+		t.Fatal("expected not ready with only one of two checkers acked")
+	}
+
+	AckPreCheck(pod, "op-1", "checkpoint-checker")
+	if !ReadyForOperation(*pod, "op-1") {
+		t.Fatal("expected ready once every registered checker has acked")
+	}
+}
+
+func TestPreCheckPassedNoCheckersRegistered(t *testing.T) {
+	pod := &corev1.Pod{}
+	if !ReadyForOperation(*pod, "op-1") {
+		t.Fatal("expected ready when no pre-check was ever requested")
+	}
+
+	RequestPreCheck(pod, "op-2", "t0", nil)
+	if !PreCheckPassed(*pod, "op-2") {
+		t.Fatal("expected pre-check requested with an empty checker set to pass vacuously")
+	}
+}
+
+func TestOperationInProgress(t *testing.T) {
+	pod := &corev1.Pod{}
+	StampOperationStart(pod, "op-1", OperationTypeRestart, "t0")
+	RequestPostCheck(pod, "op-1", "t1", []string{"drain-checker"})
+
+	if !OperationInProgress(*pod, "op-1") {
+		t.Fatal("expected operation in progress before post-check acked")
+	}
+
+	AckPostCheck(pod, "op-1", "drain-checker")
+	if OperationInProgress(*pod, "op-1") {
+		t.Fatal("expected operation no longer in progress once post-check passed")
+	}
+}
@@ -18,6 +18,8 @@ package pod
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -25,6 +27,10 @@ import (
 )
 
 // ContainerRestarted return true when there is any container in the pod that gets restarted
+//
+// Deprecated: use ClassifyRestarts, which distinguishes transient from fatal
+// restarts and respects a configured RestartBudget, instead of collapsing
+// every restart into a single bool.
 func ContainerRestarted(pod corev1.Pod) bool {
 	if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
 		for j := range pod.Status.InitContainerStatuses {
@@ -100,7 +106,11 @@ func addEnvVarIfNotExists(c *corev1.Container, e corev1.EnvVar) {
 	c.Env = append([]corev1.EnvVar{e}, c.Env...)
 }
 
-// AddLWSVariables adds LWS_LEADER_ADDRESS environment variable to every container.
+// AddLWSVariables adds the LWS rendezvous environment variables (LWS_LEADER_ADDRESS,
+// LWS_GROUP_SIZE, LWS_WORKER_INDEX, LWS_GROUP_INDEX, LWS_GLOBAL_RANK and
+// LWS_PEER_ADDRESSES, plus LWS_WORLD_SIZE when the pod carries WorldSizeLabelKey)
+// to every container, so torchrun/MPI-style launchers can start without a
+// wrapper script.
 func AddLWSVariables(pod *corev1.Pod) error {
 	lwsName, found := pod.Labels[leaderworkerset.SetNameLabelKey]
 	if !found {
@@ -111,20 +121,72 @@ func AddLWSVariables(pod *corev1.Pod) error {
 	if !found {
 		return fmt.Errorf("Failure constructing environment variables, no group index label found for pod %v", pod.Name)
 	}
+	groupIndexInt, err := strconv.Atoi(groupIndex)
+	if err != nil {
+		return fmt.Errorf("failure constructing environment variables, invalid group index %q for pod %v", groupIndex, pod.Name)
+	}
+
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return fmt.Errorf("failure constructing environment variables, no worker index label found for pod %v", pod.Name)
+	}
+	workerIndexInt, err := strconv.Atoi(workerIndex)
+	if err != nil {
+		return fmt.Errorf("failure constructing environment variables, invalid worker index %q for pod %v", workerIndex, pod.Name)
+	}
+
+	groupSize, found := pod.Labels[leaderworkerset.GroupSizeLabelKey]
+	if !found {
+		return fmt.Errorf("failure constructing environment variables, no group size label found for pod %v", pod.Name)
+	}
+	groupSizeInt, err := strconv.Atoi(groupSize)
+	if err != nil {
+		return fmt.Errorf("failure constructing environment variables, invalid group size %q for pod %v", groupSize, pod.Name)
+	}
 
 	// The headless service name is assumed to be the same as the LWS name.
 	// See function [createHeadlessServiceIfNotExists](sigs.k8s.io/lws/pkg/controllers/leaderworkerset_controller.go).
-	leaderAddressEnvVar := corev1.EnvVar{
-		Name:  leaderworkerset.LwsLeaderAddress,
-		Value: fmt.Sprintf("%s-%s.%s.%s", lwsName, groupIndex, lwsName, pod.ObjectMeta.Namespace),
+	leaderAddress := fmt.Sprintf("%s-%s.%s.%s", lwsName, groupIndex, lwsName, pod.ObjectMeta.Namespace)
+
+	envVars := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsLeaderAddress, Value: leaderAddress},
+		{Name: leaderworkerset.LwsGroupSize, Value: groupSize},
+		{Name: leaderworkerset.LwsWorkerIndex, Value: workerIndex},
+		{Name: leaderworkerset.LwsGroupIndex, Value: groupIndex},
+		{Name: leaderworkerset.LwsGlobalRank, Value: strconv.Itoa(groupIndexInt*groupSizeInt + workerIndexInt)},
+		{Name: leaderworkerset.LwsPeerAddresses, Value: strings.Join(peerAddresses(lwsName, groupIndex, groupSizeInt, pod.ObjectMeta.Namespace), ",")},
+	}
+
+	if worldSize, found := pod.Labels[leaderworkerset.WorldSizeLabelKey]; found {
+		envVars = append(envVars, corev1.EnvVar{Name: leaderworkerset.LwsWorldSize, Value: worldSize})
 	}
 
 	for i := range pod.Spec.Containers {
-		addEnvVarIfNotExists(&pod.Spec.Containers[i], leaderAddressEnvVar)
+		for _, e := range envVars {
+			addEnvVarIfNotExists(&pod.Spec.Containers[i], e)
+		}
 	}
 	for i := range pod.Spec.InitContainers {
-		addEnvVarIfNotExists(&pod.Spec.InitContainers[i], leaderAddressEnvVar)
+		for _, e := range envVars {
+			addEnvVarIfNotExists(&pod.Spec.InitContainers[i], e)
+		}
 	}
 
 	return nil
 }
+
+// peerAddresses returns the DNS names, ordered by worker index, of every pod
+// in a group of size groupSize belonging to lwsName's groupIndex'th group. The
+// leader, at index 0, is addressed by its own pod name "<lwsName>-<groupIndex>",
+// the same address as LWS_LEADER_ADDRESS.
+func peerAddresses(lwsName, groupIndex string, groupSize int, namespace string) []string {
+	addresses := make([]string, groupSize)
+	for i := 0; i < groupSize; i++ {
+		if i == 0 {
+			addresses[i] = fmt.Sprintf("%s-%s.%s.%s", lwsName, groupIndex, lwsName, namespace)
+			continue
+		}
+		addresses[i] = fmt.Sprintf("%s-%s-%d.%s.%s", lwsName, groupIndex, i, lwsName, namespace)
+	}
+	return addresses
+}
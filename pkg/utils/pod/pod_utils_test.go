@@ -0,0 +1,148 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func basePod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sts-1-2",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "sts",
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "2",
+				leaderworkerset.GroupSizeLabelKey:   "4",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main"}},
+		},
+	}
+}
+
+func envValue(c corev1.Container, name string) (string, bool) {
+	for _, e := range c.Env {
+		if e.Name == name {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestAddLWSVariablesComposition(t *testing.T) {
+	pod := basePod()
+
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	c := pod.Spec.Containers[0]
+	cases := map[string]string{
+		leaderworkerset.LwsLeaderAddress: "sts-1.sts.default",
+		leaderworkerset.LwsGroupSize:     "4",
+		leaderworkerset.LwsWorkerIndex:   "2",
+		leaderworkerset.LwsGroupIndex:    "1",
+		leaderworkerset.LwsGlobalRank:    "6", // groupIndex(1)*groupSize(4) + workerIndex(2)
+		leaderworkerset.LwsPeerAddresses: "sts-1.sts.default,sts-1-1.sts.default,sts-1-2.sts.default,sts-1-3.sts.default",
+	}
+	for name, want := range cases {
+		got, found := envValue(c, name)
+		if !found {
+			t.Errorf("env var %s not set", name)
+			continue
+		}
+		if got != want {
+			t.Errorf("env var %s = %q, want %q", name, got, want)
+		}
+	}
+
+	if _, found := envValue(c, leaderworkerset.LwsWorldSize); found {
+		t.Errorf("LWS_WORLD_SIZE set without %s label", leaderworkerset.WorldSizeLabelKey)
+	}
+}
+
+func TestAddLWSVariablesDoesNotOverwriteUserValue(t *testing.T) {
+	pod := basePod()
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: leaderworkerset.LwsLeaderAddress, Value: "user-set"}}
+
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	got, _ := envValue(pod.Spec.Containers[0], leaderworkerset.LwsLeaderAddress)
+	if got != "user-set" {
+		t.Errorf("LWS_LEADER_ADDRESS = %q, want user-supplied value preserved", got)
+	}
+}
+
+func TestAddLWSVariablesWorldSize(t *testing.T) {
+	pod := basePod()
+	pod.Labels[leaderworkerset.WorldSizeLabelKey] = "16"
+
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	got, found := envValue(pod.Spec.Containers[0], leaderworkerset.LwsWorldSize)
+	if !found || got != "16" {
+		t.Errorf("LWS_WORLD_SIZE = %q, found=%v, want \"16\"", got, found)
+	}
+}
+
+func TestAddLWSVariablesMissingLabel(t *testing.T) {
+	pod := basePod()
+	delete(pod.Labels, leaderworkerset.GroupSizeLabelKey)
+
+	if err := AddLWSVariables(pod); err == nil {
+		t.Error("expected error for missing group size label, got nil")
+	}
+}
+
+func TestAddMasterEnvAliases(t *testing.T) {
+	pod := basePod()
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("AddLWSVariables returned error: %v", err)
+	}
+
+	if err := AddMasterEnvAliases(pod, leaderworkerset.RendezvousConfig{}); err != nil {
+		t.Fatalf("AddMasterEnvAliases returned error: %v", err)
+	}
+	if _, found := envValue(pod.Spec.Containers[0], leaderworkerset.LwsMasterAddr); found {
+		t.Error("MASTER_ADDR set despite ExposeMasterEnv being false")
+	}
+
+	if err := AddMasterEnvAliases(pod, leaderworkerset.RendezvousConfig{ExposeMasterEnv: true}); err != nil {
+		t.Fatalf("AddMasterEnvAliases returned error: %v", err)
+	}
+	addr, found := envValue(pod.Spec.Containers[0], leaderworkerset.LwsMasterAddr)
+	if !found || addr != "sts-1.sts.default" {
+		t.Errorf("MASTER_ADDR = %q, found=%v, want leader address", addr, found)
+	}
+	port, found := envValue(pod.Spec.Containers[0], leaderworkerset.LwsMasterPort)
+	if !found || port != "29500" {
+		t.Errorf("MASTER_PORT = %q, found=%v, want default 29500", port, found)
+	}
+}
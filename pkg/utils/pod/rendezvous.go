@@ -0,0 +1,65 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// AddMasterEnvAliases additionally injects MASTER_ADDR and MASTER_PORT,
+// aliasing LWS_LEADER_ADDRESS and cfg.MasterPort, when cfg.ExposeMasterEnv is
+// set, so launchers such as torchrun or MPI can be invoked directly without a
+// wrapper script. It must run after AddLWSVariables has populated
+// LWS_LEADER_ADDRESS. It is a no-op, not an error, when cfg.ExposeMasterEnv
+// is unset, so callers can invoke it unconditionally.
+func AddMasterEnvAliases(pod *corev1.Pod, cfg leaderworkerset.RendezvousConfig) error {
+	if !cfg.ExposeMasterEnv {
+		return nil
+	}
+
+	leaderAddress := envVarValue(pod, leaderworkerset.LwsLeaderAddress)
+	if leaderAddress == "" {
+		return fmt.Errorf("failure constructing master env aliases, %s not set on pod %v; call AddLWSVariables first", leaderworkerset.LwsLeaderAddress, pod.Name)
+	}
+
+	masterPort := cfg.MasterPort
+	if masterPort == 0 {
+		masterPort = leaderworkerset.DefaultMasterPort
+	}
+
+	envVars := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsMasterAddr, Value: leaderAddress},
+		{Name: leaderworkerset.LwsMasterPort, Value: fmt.Sprintf("%d", masterPort)},
+	}
+
+	for i := range pod.Spec.Containers {
+		for _, e := range envVars {
+			addEnvVarIfNotExists(&pod.Spec.Containers[i], e)
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		for _, e := range envVars {
+			addEnvVarIfNotExists(&pod.Spec.InitContainers[i], e)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// RestartSeverity classifies whether a container restart looks like
+// something the group can ride out or something that should trigger a
+// recreate.
+type RestartSeverity string
+
+const (
+	// RestartSeverityTransient covers restarts expected to self-resolve,
+	// such as OOMKilled or a SIGTERM-driven restart.
+	RestartSeverityTransient RestartSeverity = "Transient"
+	// RestartSeverityFatal covers restarts caused by a non-zero user exit
+	// code that is not one of the known transient reasons.
+	RestartSeverityFatal RestartSeverity = "Fatal"
+)
+
+// transientExitReasons maps termination reasons to whether they mark a
+// restart as transient, i.e. caused by the runtime or the node rather than by
+// the user program failing on its own. The SIGTERM exit code is handled
+// separately via sigtermExitCode.
+var transientExitReasons = map[string]bool{
+	"OOMKilled": true,
+	"Error":     false,
+}
+
+const sigtermExitCode = 143
+
+// RestartInfo describes a single container's restart in enough detail for
+// the LWS controller to decide whether the group needs to be recreated.
+type RestartInfo struct {
+	// ContainerName is the name of the container that restarted.
+	ContainerName string
+	// ExitCode is the last termination's exit code.
+	ExitCode int32
+	// Reason is the last termination's reason, e.g. "OOMKilled", "Error".
+	Reason string
+	// Severity classifies the restart as Transient or Fatal.
+	Severity RestartSeverity
+	// WithinBudget reports whether this restart still falls within the
+	// configured restart budget for its container.
+	WithinBudget bool
+}
+
+// ClassifyRestarts inspects pod's container statuses and returns a
+// RestartInfo for every container that has restarted, classifying each
+// restart's severity. budget, if non-nil, is consulted to populate
+// WithinBudget against a rolling window of budget.WindowMinutes ending at
+// now; when nil every restart is reported as within budget. now is passed
+// in, rather than read from time.Now, so callers can test the windowing
+// deterministically.
+func ClassifyRestarts(pod corev1.Pod, budget *leaderworkerset.RestartBudget, now time.Time) []RestartInfo {
+	var infos []RestartInfo
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, stat := range statuses {
+		if stat.RestartCount == 0 {
+			continue
+		}
+		info := RestartInfo{
+			ContainerName: stat.Name,
+			WithinBudget:  withinBudget(pod, stat, budget, now),
+		}
+		if last := stat.LastTerminationState.Terminated; last != nil {
+			info.ExitCode = last.ExitCode
+			info.Reason = last.Reason
+		}
+		info.Severity = classifySeverity(info.ExitCode, info.Reason)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func classifySeverity(exitCode int32, reason string) RestartSeverity {
+	if transientExitReasons[reason] || exitCode == sigtermExitCode || exitCode == 0 {
+		return RestartSeverityTransient
+	}
+	return RestartSeverityFatal
+}
+
+// withinBudget reports whether stat's restarts still fall within budget's
+// "N restarts per M minutes" rate limit as of now. When RecordRestartObservations
+// has been tracking stat's container, the check counts actual restart
+// timestamps within the trailing window, which catches a recent burst on a
+// long-lived pod. Until a caller has called RecordRestartObservations at
+// least once for this pod, no timestamps are available yet, and the check
+// falls back to assuming restarts are spread evenly over the pod's
+// lifetime; that approximation understates a burst, so callers that need
+// accurate crash-loop detection must call RecordRestartObservations (and
+// persist the resulting annotations) once per reconcile before relying on
+// this.
+func withinBudget(pod corev1.Pod, stat corev1.ContainerStatus, budget *leaderworkerset.RestartBudget, now time.Time) bool {
+	if budget == nil || budget.MaxRestarts == 0 {
+		return true
+	}
+	if budget.WindowMinutes == 0 {
+		return int32(stat.RestartCount) <= budget.MaxRestarts
+	}
+
+	window := time.Duration(budget.WindowMinutes) * time.Minute
+
+	if _, timestamps := decodeRestartTracking(pod.Annotations[restartTrackingAnnotationPrefix+stat.Name]); timestamps != nil {
+		return int32(restartsSince(timestamps, now.Add(-window))) <= budget.MaxRestarts
+	}
+
+	age := now.Sub(pod.CreationTimestamp.Time)
+	if age <= 0 {
+		age = window
+	}
+	if age <= window {
+		return int32(stat.RestartCount) <= budget.MaxRestarts
+	}
+
+	restartsPerWindow := float64(stat.RestartCount) * window.Seconds() / age.Seconds()
+	return restartsPerWindow <= float64(budget.MaxRestarts)
+}
+
+// restartTrackingAnnotationPrefix namespaces the per-container annotation
+// RecordRestartObservations uses to remember when each of a container's
+// restarts happened, since the kubelet only retains an all-time
+// RestartCount and the single most recent termination.
+const restartTrackingAnnotationPrefix = "restart-tracking.lws.sigs.k8s.io/"
+
+// maxTrackedRestartTimestamps bounds how many recent restart timestamps are
+// kept per container, so the annotation can't grow without bound for a
+// container that keeps restarting over a long pod lifetime.
+const maxTrackedRestartTimestamps = 50
+
+// RecordRestartObservations compares each container's current RestartCount
+// in pod.Status against the count last recorded in its restart-tracking
+// annotation and, for every restart observed since then, appends now as
+// that restart's timestamp. Callers, typically the leader/worker pod
+// controllers, must call this once per reconcile, before consulting
+// ClassifyRestarts, and persist the mutated pod (e.g. via Patch); without
+// that, withinBudget falls back to an all-time-average approximation that
+// can hide a recent restart burst.
+func RecordRestartObservations(pod *corev1.Pod, now time.Time) {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, stat := range statuses {
+		recordRestartObservation(pod, stat.Name, stat.RestartCount, now)
+	}
+}
+
+func recordRestartObservation(pod *corev1.Pod, containerName string, currentCount int32, now time.Time) {
+	key := restartTrackingAnnotationPrefix + containerName
+	lastCount, timestamps := decodeRestartTracking(pod.Annotations[key])
+	if currentCount <= lastCount {
+		return
+	}
+
+	for i := int32(0); i < currentCount-lastCount; i++ {
+		timestamps = append(timestamps, now)
+	}
+	if len(timestamps) > maxTrackedRestartTimestamps {
+		timestamps = timestamps[len(timestamps)-maxTrackedRestartTimestamps:]
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[key] = encodeRestartTracking(currentCount, timestamps)
+}
+
+// restartsSince counts how many of timestamps fall after cutoff.
+func restartsSince(timestamps []time.Time, cutoff time.Time) int {
+	count := 0
+	for _, ts := range timestamps {
+		if ts.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+// decodeRestartTracking parses a restart-tracking annotation value encoded
+// by encodeRestartTracking. A nil timestamps slice, as opposed to an empty
+// one, distinguishes "never recorded" from "recorded with zero restarts so
+// far", which withinBudget uses to decide whether tracking data exists at
+// all.
+func decodeRestartTracking(value string) (int32, []time.Time) {
+	if value == "" {
+		return 0, nil
+	}
+	parts := strings.SplitN(value, ";", 2)
+	count, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return 0, nil
+	}
+
+	timestamps := []time.Time{}
+	if len(parts) == 2 && parts[1] != "" {
+		for _, s := range strings.Split(parts[1], ",") {
+			ts, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				continue
+			}
+			timestamps = append(timestamps, ts)
+		}
+	}
+	return int32(count), timestamps
+}
+
+func encodeRestartTracking(count int32, timestamps []time.Time) string {
+	strs := make([]string, len(timestamps))
+	for i, ts := range timestamps {
+		strs[i] = ts.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%d;%s", count, strings.Join(strs, ","))
+}
+
+// ShouldRecreateGroup reports whether, given policy and the restarts observed
+// on pod, the group containing pod should be recreated. leaderRestarted
+// indicates whether the restart under consideration happened on the group's
+// leader pod rather than a worker.
+func ShouldRecreateGroup(policy leaderworkerset.RestartPolicy, restarts []RestartInfo, leaderRestarted bool) bool {
+	if len(restarts) == 0 {
+		return false
+	}
+
+	switch policy {
+	case leaderworkerset.RestartPolicyNone:
+		return false
+	case leaderworkerset.RestartPolicyRecreateGroupOnFatalOnly:
+		for _, r := range restarts {
+			if r.Severity == RestartSeverityFatal || !r.WithinBudget {
+				return true
+			}
+		}
+		return false
+	case leaderworkerset.RestartPolicyRecreateGroupOnLeaderRestart:
+		return leaderRestarted
+	case leaderworkerset.RestartPolicyRecreateGroupOnAnyRestart, "":
+		return true
+	default:
+		return true
+	}
+}
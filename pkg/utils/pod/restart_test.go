@@ -0,0 +1,109 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// TestWithinBudgetRollingWindow guards against treating MaxRestarts as an
+// all-time cap: a pod that has run far longer than the budget's window, with
+// restarts spread thinly across its lifetime, must still be judged within
+// budget even though its all-time RestartCount exceeds MaxRestarts.
+func TestWithinBudgetRollingWindow(t *testing.T) {
+	now := time.Now()
+	budget := &leaderworkerset.RestartBudget{MaxRestarts: 2, WindowMinutes: 10}
+
+	longRunningSparse := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-30 * 24 * time.Hour))},
+	}
+	// 100 restarts over 30 days is roughly 0.023 restarts per 10-minute
+	// window, well within a budget of 2 per 10 minutes.
+	sparseStat := corev1.ContainerStatus{RestartCount: 100}
+	if !withinBudget(longRunningSparse, sparseStat, budget, now) {
+		t.Errorf("expected sparse long-lived restarts to be within budget, got outside")
+	}
+
+	young := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Minute))},
+	}
+	burstStat := corev1.ContainerStatus{RestartCount: 5}
+	if withinBudget(young, burstStat, budget, now) {
+		t.Errorf("expected a 5-restart burst within the window to be outside budget, got within")
+	}
+}
+
+// TestWithinBudgetDetectsRecentBurstOnLongLivedPod guards against the
+// all-time-average approximation masking a crash loop: a pod that has run
+// for 30 days with only 2 old restarts, then 10 restarts in the last 10
+// minutes, must be reported outside budget once those restarts have been
+// recorded via RecordRestartObservations, even though its lifetime average
+// alone would look well within budget.
+func TestWithinBudgetDetectsRecentBurstOnLongLivedPod(t *testing.T) {
+	now := time.Now()
+	budget := &leaderworkerset.RestartBudget{MaxRestarts: 2, WindowMinutes: 10}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-30 * 24 * time.Hour))},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", RestartCount: 2}},
+		},
+	}
+	RecordRestartObservations(pod, now.Add(-29*24*time.Hour))
+
+	pod.Status.ContainerStatuses[0].RestartCount = 12
+	RecordRestartObservations(pod, now.Add(-5*time.Minute))
+
+	if withinBudget(*pod, pod.Status.ContainerStatuses[0], budget, now) {
+		t.Error("expected a recent 10-restart burst to be outside budget once recorded, got within")
+	}
+}
+
+func TestRecordRestartObservationsOnlyAppendsNewRestarts(t *testing.T) {
+	now := time.Now()
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", RestartCount: 1}},
+		},
+	}
+	RecordRestartObservations(pod, now)
+	RecordRestartObservations(pod, now.Add(time.Minute)) // no new restarts since last observation
+
+	_, timestamps := decodeRestartTracking(pod.Annotations[restartTrackingAnnotationPrefix+"main"])
+	if len(timestamps) != 1 {
+		t.Fatalf("got %d tracked timestamps, want 1 (no new restarts observed on the second call)", len(timestamps))
+	}
+}
+
+func TestWithinBudgetNilOrUnboundedBudget(t *testing.T) {
+	now := time.Now()
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}}
+	stat := corev1.ContainerStatus{RestartCount: 1000}
+
+	if !withinBudget(pod, stat, nil, now) {
+		t.Errorf("expected nil budget to mean unbounded, got outside budget")
+	}
+	if !withinBudget(pod, stat, &leaderworkerset.RestartBudget{}, now) {
+		t.Errorf("expected zero-value MaxRestarts to mean unbounded, got outside budget")
+	}
+}
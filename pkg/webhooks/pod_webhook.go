@@ -0,0 +1,185 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+)
+
+// PodWebhook mutates and validates LWS-owned pods on admission: it injects
+// the rendezvous environment variables and, where enabled, the pod-index
+// labels, and it rejects pods that try to set any of those reserved values
+// themselves. It holds a client so it can fetch the owning LeaderWorkerSet
+// for settings, such as the env-override annotation, that must be read off
+// the LWS rather than trusted from the pod being admitted.
+type PodWebhook struct {
+	client.Client
+}
+
+// SetupWebhookWithManager registers PodWebhook's mutating and validating
+// handlers with mgr.
+func (w *PodWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	w.Client = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// ownerLWS fetches the LeaderWorkerSet named by pod's SetNameLabelKey label.
+// It returns a nil LeaderWorkerSet, not an error, both when the pod carries
+// no such label and when the named LeaderWorkerSet no longer exists (the pod
+// is about to be garbage collected anyway), so callers can treat either case
+// as "no LWS-level configuration available" rather than failing admission.
+func (w *PodWebhook) ownerLWS(ctx context.Context, pod *corev1.Pod) (*leaderworkerset.LeaderWorkerSet, error) {
+	name, ok := pod.Labels[leaderworkerset.SetNameLabelKey]
+	if !ok {
+		return nil, nil
+	}
+	var lws leaderworkerset.LeaderWorkerSet
+	if err := w.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, &lws); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching owning LeaderWorkerSet %s/%s: %w", pod.Namespace, name, err)
+	}
+	return &lws, nil
+}
+
+var _ admission.CustomDefaulter = &PodWebhook{}
+var _ admission.CustomValidator = &PodWebhook{}
+
+// Default implements admission.CustomDefaulter. It is the sole call site
+// that chains AddLWSVariables with AddPodIndexLabels and the other pod
+// mutators, so the ordering those mutations must happen in lives in one
+// place rather than being re-derived by every caller.
+func (w *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod but got %T", obj)
+	}
+
+	if _, ownedByLWS := pod.Labels[leaderworkerset.SetNameLabelKey]; !ownedByLWS {
+		return nil
+	}
+
+	if err := podutils.AddLWSVariables(pod); err != nil {
+		return err
+	}
+	if err := podutils.AddPodIndexLabels(pod); err != nil {
+		return err
+	}
+
+	lws, err := w.ownerLWS(ctx, pod)
+	if err != nil {
+		return err
+	}
+	if lws == nil {
+		// The owning LeaderWorkerSet is gone or not yet visible to this
+		// webhook's cache; the mutations above, which only need the pod
+		// itself, still applied. Everything below needs LWS-level config
+		// and is skipped rather than failing admission.
+		return nil
+	}
+
+	if err := podutils.AddMasterEnvAliases(pod, lws.Spec.LeaderWorkerTemplate.RendezvousConfig); err != nil {
+		return err
+	}
+
+	netCfg := lws.Spec.LeaderWorkerTemplate.NetworkConfig
+	if netCfg != nil && netCfg.LeaderDNSPrewarm != nil && !podutils.LeaderPod(*pod) {
+		if err := podutils.AddLeaderDNSPrewarmInitContainer(pod, *netCfg.LeaderDNSPrewarm); err != nil {
+			return err
+		}
+	}
+
+	if lws.Spec.SchedulingPolicy != nil {
+		if err := podutils.AddGangSchedulingLabels(pod, lws.Spec.SchedulingPolicy.Backend); err != nil {
+			return err
+		}
+		// Only the initial value can be set here: Containers[].Env is
+		// immutable once the pod is created, so this always reads false.
+		// Once the group's gang-scheduling object reports every member
+		// scheduled, PodGroupReconciler surfaces that by patching the
+		// GangReadyAnnotationKey annotation on the pods instead, since
+		// annotations, unlike Env, can be updated after creation.
+		podutils.SetGangReadyEnvVar(pod, false)
+	}
+
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator. It rejects pods that
+// declare a reserved LWS_* environment variable or a reserved pod-index
+// label themselves, so misconfiguration surfaces at admission time instead
+// of being silently overridden by Default.
+func (w *PodWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod but got %T", obj)
+	}
+	if _, ownedByLWS := pod.Labels[leaderworkerset.SetNameLabelKey]; !ownedByLWS {
+		return nil, nil
+	}
+
+	if err := podutils.ValidatePodIndexLabelsUnset(*pod); err != nil {
+		return nil, err
+	}
+
+	lws, err := w.ownerLWS(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	var lwsAnnotations map[string]string
+	if lws != nil {
+		lwsAnnotations = lws.Annotations
+	}
+	if !podutils.AllowsEnvOverride(lwsAnnotations) {
+		if errs := podutils.ValidateNoReservedEnvVars(pod.Spec, field.NewPath("spec")); len(errs) > 0 {
+			return nil, errs.ToAggregate()
+		}
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator with the same checks
+// as ValidateCreate; LWS pods are replaced rather than mutated in place, but
+// the check is cheap enough to run on both paths for defense in depth.
+func (w *PodWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return w.ValidateCreate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator. There is nothing to
+// validate on delete.
+func (w *PodWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}